@@ -1,9 +1,15 @@
 package querier
 
 import (
+	"container/heap"
 	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
 	"net/http"
 	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -12,8 +18,8 @@ import (
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/concurrency"
 	"github.com/grafana/dskit/user"
-
-	"github.com/grafana/loki/v3/pkg/storage/stores/index/seriesvolume"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	"github.com/gogo/status"
 	"github.com/grafana/dskit/httpgrpc"
@@ -40,9 +46,27 @@ var defaultQuorumConfig = ring.DoUntilQuorumConfig{
 	// Nothing here
 }
 
+// zoneResultsQuorumConfig returns the ring.DoUntilQuorumConfig to use for metadata queries
+// (Label, Series, Stats, Volume, DetectedLabel). When zone-aware quorum results are enabled,
+// requests are minimized so that, as soon as a quorum of zones has returned a healthy response,
+// the remaining in-flight requests are cancelled instead of waiting on every replica. Ingesters
+// without zone information are unaffected, since the ring falls back to a flat quorum over all
+// instances in that case.
+func zoneResultsQuorumConfig(zoneResultsQuorum bool) ring.DoUntilQuorumConfig {
+	if !zoneResultsQuorum {
+		return defaultQuorumConfig
+	}
+	return ring.DoUntilQuorumConfig{
+		MinimizeRequests: true,
+	}
+}
+
 type responseFromIngesters struct {
 	addr     string
 	response interface{}
+	// err is only ever populated for best-effort (partial) queries, where a per-ingester failure
+	// doesn't abort the whole call. See forGivenIngestersPartial.
+	err error
 }
 
 // IngesterQuerier helps with querying the ingesters.
@@ -53,6 +77,9 @@ type IngesterQuerier struct {
 	getShardCountForTenant func(string) int
 	pool                   *ring_client.Pool
 	logger                 log.Logger
+
+	partialQueryFailures *prometheus.CounterVec
+	affinityCache        *IngesterAffinityCache
 }
 
 func NewIngesterQuerier(querierConfig Config, clientCfg client.Config, ring ring.ReadRing, partitionRing *ring.PartitionInstanceRing, getShardCountForTenant func(string) int, metricsNamespace string, logger log.Logger) (*IngesterQuerier, error) {
@@ -73,6 +100,15 @@ func newIngesterQuerier(querierConfig Config, clientCfg client.Config, ring ring
 		getShardCountForTenant: getShardCountForTenant, // limits?
 		pool:                   clientpool.NewPool("ingester", clientCfg.PoolConfig, ring, clientFactory, util_log.Logger, metricsNamespace),
 		logger:                 logger,
+		partialQueryFailures: promauto.With(prometheus.DefaultRegisterer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "querier_ingester_partial_query_failures_total",
+			Help:      "Total number of per-ingester failures tolerated by best-effort (partial) metadata queries, by ingester address and gRPC status code.",
+		}, []string{"ingester", "status_code"}),
+	}
+
+	if querierConfig.IngesterQueryAffinityTTL > 0 {
+		iq.affinityCache = NewIngesterAffinityCache(querierConfig.IngesterQueryAffinityTTL, metricsNamespace)
 	}
 
 	err := services.StartAndAwaitRunning(context.Background(), iq.pool)
@@ -86,9 +122,23 @@ func newIngesterQuerier(querierConfig Config, clientCfg client.Config, ring ring
 type ctxKeyType string
 
 const (
-	partitionCtxKey ctxKeyType = "partitionCtx"
+	partitionCtxKey              ctxKeyType = "partitionCtx"
+	queryWarningsCtxKey          ctxKeyType = "queryWarnings"
+	allowPartialMetadataQueryKey ctxKeyType = "allowPartialMetadataQuery"
+	// AllowPartialMetadataQueriesHeader lets a single request opt in (or out) of best-effort
+	// metadata queries, overriding Config.PartialDataMetadataQueries for that request only. The
+	// querier frontend is expected to translate this header into a ContextWithAllowPartialMetadataQueries
+	// call before the request reaches the IngesterQuerier.
+	AllowPartialMetadataQueriesHeader = "X-Loki-Allow-Partial-Metadata-Queries"
 )
 
+// ContextWithAllowPartialMetadataQueries overrides, for this context only, whether metadata
+// queries should tolerate individual ingester failures. It takes precedence over
+// Config.PartialDataMetadataQueries.
+func ContextWithAllowPartialMetadataQueries(ctx context.Context, allow bool) context.Context {
+	return context.WithValue(ctx, allowPartialMetadataQueryKey, allow)
+}
+
 type PartitionContext struct {
 	isPartitioned bool
 	ingestersUsed map[string]PartitionIngesterUsed
@@ -164,6 +214,228 @@ func ExtractPartitionContext(ctx context.Context) *PartitionContext {
 	return v
 }
 
+// PartialFailure describes an ingester that failed to respond to a best-effort metadata query.
+// The overall query still succeeds as long as enough of the other replicas responded.
+type PartialFailure struct {
+	Addr string
+	Err  error
+}
+
+// QueryWarnings accumulates non-fatal problems encountered while executing a query across
+// ingesters, such as the per-ingester failures tolerated by best-effort metadata queries. Each
+// IngesterQuerier metadata method makes sure its request context carries one via
+// ensureQueryWarnings. A caller that attached its own with ContextWithQueryWarnings reads the
+// failures back with ExtractQueryWarnings; otherwise only their count is logged once the query
+// completes. Nothing in this package adds them to an HTTP response body.
+type QueryWarnings struct {
+	mtx             sync.Mutex
+	partialFailures []PartialFailure
+}
+
+// ContextWithQueryWarnings returns a context a *QueryWarnings can later be recovered from via
+// ExtractQueryWarnings, so that warnings gathered deep inside the ingester fan-out can bubble up
+// to the caller.
+func ContextWithQueryWarnings(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queryWarningsCtxKey, &QueryWarnings{})
+}
+
+// ExtractQueryWarnings returns the *QueryWarnings stored in ctx, or a usable empty one if none
+// was attached with ContextWithQueryWarnings.
+func ExtractQueryWarnings(ctx context.Context) *QueryWarnings {
+	v, ok := ctx.Value(queryWarningsCtxKey).(*QueryWarnings)
+	if !ok {
+		return &QueryWarnings{}
+	}
+	return v
+}
+
+// ensureQueryWarnings attaches a *QueryWarnings to ctx if the caller hasn't already attached one,
+// so that addPartialFailures always has somewhere to write to. When it attached one itself, the
+// returned function logs how many failures were collected; otherwise the returned function does
+// nothing and the failures are left to the caller. Call the returned function once the query this
+// ctx covers has completed.
+func (q *IngesterQuerier) ensureQueryWarnings(ctx context.Context) (context.Context, func()) {
+	if _, ok := ctx.Value(queryWarningsCtxKey).(*QueryWarnings); ok {
+		return ctx, func() {}
+	}
+	ctx = ContextWithQueryWarnings(ctx)
+	return ctx, func() {
+		failures := ExtractQueryWarnings(ctx).PartialFailures()
+		if len(failures) == 0 {
+			return
+		}
+		level.Warn(q.logger).Log("msg", "query completed with partial ingester failures", "count", len(failures))
+	}
+}
+
+func (w *QueryWarnings) addPartialFailures(failures []PartialFailure) {
+	if len(failures) == 0 {
+		return
+	}
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.partialFailures = append(w.partialFailures, failures...)
+}
+
+// PartialFailures returns the ingester addresses and errors tolerated by best-effort queries on
+// this request so far.
+func (w *QueryWarnings) PartialFailures() []PartialFailure {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return append([]PartialFailure(nil), w.partialFailures...)
+}
+
+// queryFingerprint identifies the shape of a query - tenant plus a fingerprint of its normalized
+// LogQL AST and time-range bucket - so that follow-up range-extension queries from the same
+// dashboard panel can be recognized as "the same query" by IngesterAffinityCache.
+type queryFingerprint struct {
+	tenantID    string
+	fingerprint uint64
+}
+
+const queryFingerprintCtxKey ctxKeyType = "queryFingerprint"
+
+// ContextWithQueryFingerprint attaches a stable identifier for a query's shape so that
+// IngesterQuerier can remember which ingesters served it and prefer them again on follow-up
+// requests, via IngesterAffinityCache. Callers are expected to derive fingerprint from the
+// normalized query AST and a time-range bucket, so that a dashboard panel being auto-refreshed
+// or range-extended produces the same value each time.
+func ContextWithQueryFingerprint(ctx context.Context, tenantID string, fingerprint uint64) context.Context {
+	return context.WithValue(ctx, queryFingerprintCtxKey, queryFingerprint{tenantID: tenantID, fingerprint: fingerprint})
+}
+
+func queryFingerprintFromContext(ctx context.Context) (queryFingerprint, bool) {
+	qf, ok := ctx.Value(queryFingerprintCtxKey).(queryFingerprint)
+	return qf, ok
+}
+
+type affinityCacheEntry struct {
+	addrs     []string
+	expiresAt time.Time
+}
+
+// IngesterAffinityCache remembers, for a configurable TTL, which ingester addresses served a
+// given (tenantID, query fingerprint) shape, so that follow-up range-extension queries from the
+// same dashboard hit the same replicas and benefit from their warm caches. Unlike PartitionContext,
+// which only reuses ingesters within a single request chain (Select -> GetChunkIDs), this cache is
+// shared across requests for the lifetime of its TTL.
+type IngesterAffinityCache struct {
+	ttl time.Duration
+
+	mtx     sync.Mutex
+	entries map[string]affinityCacheEntry
+
+	hits      prometheus.Counter
+	misses    prometheus.Counter
+	evictions prometheus.Counter
+}
+
+// NewIngesterAffinityCache creates an IngesterAffinityCache whose entries expire after ttl.
+func NewIngesterAffinityCache(ttl time.Duration, metricsNamespace string) *IngesterAffinityCache {
+	return &IngesterAffinityCache{
+		ttl:     ttl,
+		entries: make(map[string]affinityCacheEntry),
+		hits: promauto.With(prometheus.DefaultRegisterer).NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "querier_ingester_affinity_cache_hits_total",
+			Help:      "Total number of ingester affinity cache hits.",
+		}),
+		misses: promauto.With(prometheus.DefaultRegisterer).NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "querier_ingester_affinity_cache_misses_total",
+			Help:      "Total number of ingester affinity cache misses.",
+		}),
+		evictions: promauto.With(prometheus.DefaultRegisterer).NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "querier_ingester_affinity_cache_evictions_total",
+			Help:      "Total number of ingester affinity cache entries evicted for being stale.",
+		}),
+	}
+}
+
+func affinityCacheKey(qf queryFingerprint) string {
+	return qf.tenantID + "/" + strconv.FormatUint(qf.fingerprint, 16)
+}
+
+// Get returns the ingester addresses previously recorded for qf, provided the entry hasn't
+// expired.
+func (c *IngesterAffinityCache) Get(qf queryFingerprint) ([]string, bool) {
+	key := affinityCacheKey(qf)
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		c.misses.Inc()
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		c.evictions.Inc()
+		c.misses.Inc()
+		return nil, false
+	}
+
+	c.hits.Inc()
+	return entry.addrs, true
+}
+
+// Put records the ingester addresses that served qf, for reuse by follow-up queries within the
+// cache's TTL.
+func (c *IngesterAffinityCache) Put(qf queryFingerprint, addrs []string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.entries[affinityCacheKey(qf)] = affinityCacheEntry{
+		addrs:     append([]string(nil), addrs...),
+		expiresAt: time.Now().Add(c.ttl),
+	}
+}
+
+// rendezvousSelect deterministically picks the n highest-scoring addresses for fingerprint fp
+// using rendezvous (highest random weight) hashing, so independent queriers agree on the same
+// ingesters for a given query shape without needing to share a ring lookup or cache.
+func rendezvousSelect(addrs []string, fp uint64, n int) []string {
+	type scored struct {
+		addr  string
+		score uint64
+	}
+
+	scores := make([]scored, 0, len(addrs))
+	for _, addr := range addrs {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(addr))
+		_ = binary.Write(h, binary.LittleEndian, fp)
+		scores = append(scores, scored{addr: addr, score: h.Sum64()})
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].score != scores[j].score {
+			return scores[i].score > scores[j].score
+		}
+		return scores[i].addr < scores[j].addr
+	})
+
+	if n > len(scores) {
+		n = len(scores)
+	}
+	selected := make([]string, n)
+	for i := 0; i < n; i++ {
+		selected[i] = scores[i].addr
+	}
+	return selected
+}
+
+// allowPartialMetadataQueries reports whether metadata queries (Label, Series, Stats, Volume,
+// DetectedLabel) should tolerate individual ingester failures instead of failing the whole
+// request, taking the per-request override set via ContextWithAllowPartialMetadataQueries into
+// account before falling back to Config.PartialDataMetadataQueries.
+func (q *IngesterQuerier) allowPartialMetadataQueries(ctx context.Context) bool {
+	if allow, ok := ctx.Value(allowPartialMetadataQueryKey).(bool); ok {
+		return allow
+	}
+	return q.querierConfig.PartialDataMetadataQueries
+}
+
 // forAllIngesters runs f, in parallel, for all ingesters
 func (q *IngesterQuerier) forAllIngesters(ctx context.Context, f func(context.Context, logproto.QuerierClient) (interface{}, error)) ([]responseFromIngesters, error) {
 	if q.querierConfig.QueryPartitionIngesters {
@@ -189,9 +461,155 @@ func (q *IngesterQuerier) forAllIngesters(ctx context.Context, f func(context.Co
 		return nil, err
 	}
 
+	if q.affinityCache != nil {
+		if qf, ok := queryFingerprintFromContext(ctx); ok {
+			return q.forAllIngestersWithAffinity(ctx, qf, replicationSet, f)
+		}
+	}
+
 	return q.forGivenIngesters(ctx, replicationSet, defaultQuorumConfig, f)
 }
 
+// forAllIngestersWithAffinity queries every instance in replicationSet, same as forAllIngesters
+// without a cache - full coverage is required here because, unlike metadata queries, any ingester
+// can hold unique unflushed chunks for a matching stream, so skipping one would silently drop log
+// data. The affinity cache only reorders the instance list as a priority hint, preferring the
+// addresses that served qf last time, so a warm ingester's connection and chunk cache are reused
+// first. On a cache miss it falls back to a deterministic rendezvous-hash ordering over the
+// currently healthy instances, so independent queriers converge on the same ordering without a
+// shared cache or an extra ring lookup. The addresses that actually responded are (re)recorded in
+// the cache for next time.
+func (q *IngesterQuerier) forAllIngestersWithAffinity(ctx context.Context, qf queryFingerprint, replicationSet ring.ReplicationSet, f func(context.Context, logproto.QuerierClient) (interface{}, error)) ([]responseFromIngesters, error) {
+	activeAddrs := make([]string, 0, len(replicationSet.Instances))
+	for _, inst := range replicationSet.Instances {
+		if inst.State == ring.ACTIVE {
+			activeAddrs = append(activeAddrs, inst.Addr)
+		}
+	}
+	sort.Strings(activeAddrs)
+
+	priority := make(map[string]int, len(activeAddrs))
+	if cachedAddrs, ok := q.affinityCache.Get(qf); ok {
+		for i, addr := range cachedAddrs {
+			priority[addr] = i
+		}
+	} else {
+		for i, addr := range rendezvousSelect(activeAddrs, qf.fingerprint, len(activeAddrs)) {
+			priority[addr] = i
+		}
+	}
+
+	// Preserve the original replication set's quorum fields (MaxErrors, MaxUnavailableZones,
+	// ZoneAwarenessEnabled) - only the instance ordering changes.
+	orderedSet := replicationSet
+	orderedSet.Instances = append([]ring.InstanceDesc(nil), replicationSet.Instances...)
+	sort.SliceStable(orderedSet.Instances, func(i, j int) bool {
+		pi, iKnown := priority[orderedSet.Instances[i].Addr]
+		pj, jKnown := priority[orderedSet.Instances[j].Addr]
+		if iKnown != jKnown {
+			return iKnown
+		}
+		return pi < pj
+	})
+
+	resps, err := q.forGivenIngesters(ctx, orderedSet, defaultQuorumConfig, f)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := make([]string, 0, len(resps))
+	for _, resp := range resps {
+		addrs = append(addrs, resp.addr)
+	}
+	q.affinityCache.Put(qf, addrs)
+
+	return resps, nil
+}
+
+// forAllIngestersWithQuorum behaves like forAllIngesters, but for metadata queries (Label, Series,
+// Stats, Volume, DetectedLabel) which can tolerate a subset of replicas responding. When
+// querierConfig.ZoneResultsQuorumMetadata is enabled, it stops waiting as soon as a quorum of zones
+// has returned a healthy response instead of waiting for the full replica fan-out.
+func (q *IngesterQuerier) forAllIngestersWithQuorum(ctx context.Context, f func(context.Context, logproto.QuerierClient) (interface{}, error)) ([]responseFromIngesters, error) {
+	if q.querierConfig.QueryPartitionIngesters {
+		return q.forAllIngesters(ctx, f)
+	}
+
+	replicationSet, err := q.ring.GetReplicationSetForOperation(ring.Read)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.allowPartialMetadataQueries(ctx) {
+		successes, failures, err := q.forGivenIngestersPartial(ctx, replicationSet, f)
+		ExtractQueryWarnings(ctx).addPartialFailures(failures)
+		return successes, err
+	}
+
+	return q.forGivenIngesters(ctx, replicationSet, zoneResultsQuorumConfig(q.querierConfig.ZoneResultsQuorumMetadata), f)
+}
+
+// forGivenIngestersPartial runs f, in parallel, against every instance in replicationSet without
+// requiring a quorum: every successful response is returned alongside a PartialFailure for every
+// instance that errored, rather than aborting the whole call on the first failure. The call is
+// still considered an overall failure if fewer than Config.PartialDataMinSuccessFraction of the
+// replicas succeeded (defaulting to the replication set's own quorum fraction when unset).
+func (q *IngesterQuerier) forGivenIngestersPartial(ctx context.Context, replicationSet ring.ReplicationSet, f func(context.Context, logproto.QuerierClient) (interface{}, error)) ([]responseFromIngesters, []PartialFailure, error) {
+	responses, err := concurrency.ForEachJobMergeResults(ctx, replicationSet.Instances, 0, func(ctx context.Context, ingester ring.InstanceDesc) ([]responseFromIngesters, error) {
+		client, err := q.pool.GetClientFor(ingester.Addr)
+		if err != nil {
+			return []responseFromIngesters{{addr: ingester.Addr, err: err}}, nil
+		}
+		resp, err := f(ctx, client.(logproto.QuerierClient))
+		if err != nil {
+			return []responseFromIngesters{{addr: ingester.Addr, err: err}}, nil
+		}
+		return []responseFromIngesters{{addr: ingester.Addr, response: resp}}, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	successes := make([]responseFromIngesters, 0, len(responses))
+	var failures []PartialFailure
+	for _, resp := range responses {
+		if resp.err != nil {
+			q.partialQueryFailures.WithLabelValues(resp.addr, status.Code(resp.err).String()).Inc()
+			failures = append(failures, PartialFailure{Addr: resp.addr, Err: resp.err})
+			continue
+		}
+		successes = append(successes, resp)
+	}
+
+	if len(failures) == 0 {
+		return successes, nil, nil
+	}
+
+	if !q.meetsMinimumSuccessFraction(replicationSet, len(successes)) {
+		return successes, failures, fmt.Errorf("best-effort query failed: only %d of %d ingesters responded successfully", len(successes), len(replicationSet.Instances))
+	}
+
+	return successes, failures, nil
+}
+
+// meetsMinimumSuccessFraction reports whether numSuccesses out of replicationSet's replicas is
+// enough to consider a best-effort query successful, per Config.PartialDataMinSuccessFraction. A
+// non-positive configured fraction falls back to the replication set's own quorum fraction (i.e.
+// tolerating the same minority of failures ring.DoUntilQuorum would have tolerated) rather than
+// requiring every replica, so enabling best-effort queries without also tuning
+// PartialDataMinSuccessFraction doesn't regress to a stricter-than-quorum requirement.
+func (q *IngesterQuerier) meetsMinimumSuccessFraction(replicationSet ring.ReplicationSet, numSuccesses int) bool {
+	total := len(replicationSet.Instances)
+	if total == 0 {
+		return true
+	}
+	minFraction := q.querierConfig.PartialDataMinSuccessFraction
+	if minFraction <= 0 {
+		minFraction = float64(total-replicationSet.MaxErrors) / float64(total)
+	}
+	return float64(numSuccesses)/float64(total) >= minFraction
+}
+
 // forGivenIngesterSets runs f, in parallel, for given ingester sets
 func (q *IngesterQuerier) forGivenIngesterSets(ctx context.Context, replicationSet []ring.ReplicationSet, f func(context.Context, logproto.QuerierClient) (interface{}, error)) ([]responseFromIngesters, error) {
 	// Enable minimize requests if we can, so we initially query a single ingester per replication set, as each replication-set is one partition.
@@ -231,6 +649,93 @@ func (q *IngesterQuerier) forGivenIngesters(ctx context.Context, replicationSet
 	return responses, err
 }
 
+// forAllIngestersStreaming behaves like forAllIngesters, but instead of returning a slice of
+// responses it hands each one to onResponse, which folds it into the caller's accumulator (see
+// statsAccumulator, volumeAccumulator). It honors the same zone-aware quorum and best-effort
+// partial-result settings as forAllIngestersWithQuorum.
+//
+// Only best-effort queries fold each response in as it arrives: every instance queried is wanted,
+// so its raw response can be dropped right after onResponse returns. The partitioned and the plain
+// quorum paths first collect the responses ring.DoUntilQuorum settles on into a slice - exactly
+// like forGivenIngesters - and pass them to onResponse only after that, because a reply can still
+// land for an instance that's no longer needed (a normal race once quorum is reached, and
+// forGivenIngesterSets runs each partition's quorum with MinimizeRequests enabled), and folding it
+// in eagerly would double-count that replica. On those two paths every needed raw response is held
+// in memory at once until the quorum has settled.
+func (q *IngesterQuerier) forAllIngestersStreaming(ctx context.Context, onResponse func(addr string, resp interface{}) error, f func(context.Context, logproto.QuerierClient) (interface{}, error)) error {
+	if q.querierConfig.QueryPartitionIngesters {
+		results, err := q.forAllIngesters(ctx, f)
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			if err := onResponse(result.addr, result.response); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	replicationSet, err := q.ring.GetReplicationSetForOperation(ring.Read)
+	if err != nil {
+		return err
+	}
+
+	if q.allowPartialMetadataQueries(ctx) {
+		var (
+			mtx      sync.Mutex
+			failures []PartialFailure
+		)
+		_, err := concurrency.ForEachJobMergeResults(ctx, replicationSet.Instances, 0, func(ctx context.Context, ingester ring.InstanceDesc) ([]struct{}, error) {
+			client, err := q.pool.GetClientFor(ingester.Addr)
+			if err == nil {
+				var resp interface{}
+				if resp, err = f(ctx, client.(logproto.QuerierClient)); err == nil {
+					err = onResponse(ingester.Addr, resp)
+				}
+			}
+			if err != nil {
+				q.partialQueryFailures.WithLabelValues(ingester.Addr, status.Code(err).String()).Inc()
+				mtx.Lock()
+				failures = append(failures, PartialFailure{Addr: ingester.Addr, Err: err})
+				mtx.Unlock()
+			}
+			return nil, nil
+		})
+		ExtractQueryWarnings(ctx).addPartialFailures(failures)
+		if err != nil {
+			return err
+		}
+		if !q.meetsMinimumSuccessFraction(replicationSet, len(replicationSet.Instances)-len(failures)) {
+			return fmt.Errorf("best-effort query failed: only %d of %d ingesters responded successfully", len(replicationSet.Instances)-len(failures), len(replicationSet.Instances))
+		}
+		return nil
+	}
+
+	results, err := ring.DoUntilQuorum(ctx, replicationSet, zoneResultsQuorumConfig(q.querierConfig.ZoneResultsQuorumMetadata), func(ctx context.Context, ingester *ring.InstanceDesc) (responseFromIngesters, error) {
+		client, err := q.pool.GetClientFor(ingester.Addr)
+		if err != nil {
+			return responseFromIngesters{addr: ingester.Addr}, err
+		}
+		resp, err := f(ctx, client.(logproto.QuerierClient))
+		if err != nil {
+			return responseFromIngesters{addr: ingester.Addr}, err
+		}
+		return responseFromIngesters{addr: ingester.Addr, response: resp}, nil
+	}, func(responseFromIngesters) {})
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		if err := onResponse(result.addr, result.response); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (q *IngesterQuerier) SelectLogs(ctx context.Context, params logql.SelectLogParams) ([]iter.EntryIterator, error) {
 	resps, err := q.forAllIngesters(ctx, func(_ context.Context, client logproto.QuerierClient) (interface{}, error) {
 		stats.FromContext(ctx).AddIngesterReached(1)
@@ -264,7 +769,10 @@ func (q *IngesterQuerier) SelectSample(ctx context.Context, params logql.SelectS
 }
 
 func (q *IngesterQuerier) Label(ctx context.Context, req *logproto.LabelRequest) ([][]string, error) {
-	resps, err := q.forAllIngesters(ctx, func(ctx context.Context, client logproto.QuerierClient) (interface{}, error) {
+	ctx, logQueryWarnings := q.ensureQueryWarnings(ctx)
+	defer logQueryWarnings()
+
+	resps, err := q.forAllIngestersWithQuorum(ctx, func(ctx context.Context, client logproto.QuerierClient) (interface{}, error) {
 		return client.Label(ctx, req)
 	})
 	if err != nil {
@@ -345,7 +853,10 @@ func (q *IngesterQuerier) TailDisconnectedIngesters(ctx context.Context, req *lo
 }
 
 func (q *IngesterQuerier) Series(ctx context.Context, req *logproto.SeriesRequest) ([][]logproto.SeriesIdentifier, error) {
-	resps, err := q.forAllIngesters(ctx, func(ctx context.Context, client logproto.QuerierClient) (interface{}, error) {
+	ctx, logQueryWarnings := q.ensureQueryWarnings(ctx)
+	defer logQueryWarnings()
+
+	resps, err := q.forAllIngestersWithQuorum(ctx, func(ctx context.Context, client logproto.QuerierClient) (interface{}, error) {
 		return client.Series(ctx, req)
 	})
 	if err != nil {
@@ -428,8 +939,39 @@ func (q *IngesterQuerier) GetChunkIDs(ctx context.Context, from, through model.T
 	return chunkIDs, nil
 }
 
+// statsAccumulator incrementally folds index_stats.Stats responses into one running total as
+// forAllIngestersStreaming hands them over. How many raw ingester responses are held in memory at
+// once depends on the path forAllIngestersStreaming takes (see its doc).
+type statsAccumulator struct {
+	mtx   sync.Mutex
+	stats index_stats.Stats
+}
+
+func (a *statsAccumulator) Add(resp *index_stats.Stats) {
+	if resp == nil {
+		return
+	}
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.stats = index_stats.MergeStats(&a.stats, resp)
+}
+
+func (a *statsAccumulator) Result() *index_stats.Stats {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	stats := a.stats
+	return &stats
+}
+
 func (q *IngesterQuerier) Stats(ctx context.Context, _ string, from, through model.Time, matchers ...*labels.Matcher) (*index_stats.Stats, error) {
-	resps, err := q.forAllIngesters(ctx, func(ctx context.Context, querierClient logproto.QuerierClient) (interface{}, error) {
+	ctx, logQueryWarnings := q.ensureQueryWarnings(ctx)
+	defer logQueryWarnings()
+
+	acc := &statsAccumulator{}
+	err := q.forAllIngestersStreaming(ctx, func(_ string, resp interface{}) error {
+		acc.Add(resp.(*index_stats.Stats))
+		return nil
+	}, func(ctx context.Context, querierClient logproto.QuerierClient) (interface{}, error) {
 		return querierClient.GetStats(ctx, &logproto.IndexStatsRequest{
 			From:     from,
 			Through:  through,
@@ -444,22 +986,108 @@ func (q *IngesterQuerier) Stats(ctx context.Context, _ string, from, through mod
 		return nil, err
 	}
 
-	casted := make([]*index_stats.Stats, 0, len(resps))
-	for _, resp := range resps {
-		casted = append(casted, resp.response.(*index_stats.Stats))
+	return acc.Result(), nil
+}
+
+// volumeHeapItem is an entry tracked by volumeHeap, keyed by label-set name.
+type volumeHeapItem struct {
+	name   string
+	volume uint64
+}
+
+// volumeHeap is a container/heap.Interface min-heap ordered by ascending volume, used by
+// volumeAccumulator.Result to pick the highest-volume entries out of the full running totals
+// without sorting all of them.
+type volumeHeap []volumeHeapItem
+
+func (h volumeHeap) Len() int            { return len(h) }
+func (h volumeHeap) Less(i, j int) bool  { return h[i].volume < h[j].volume }
+func (h volumeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *volumeHeap) Push(x interface{}) { *h = append(*h, x.(volumeHeapItem)) }
+func (h *volumeHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// volumeAccumulator incrementally sums VolumeResponses into a running per-name total as they
+// stream in from ingesters, instead of buffering every raw response until all ingesters have
+// replied. The same label set can be split across replicas, so a name's total volume must include
+// every ingester's contribution before it can be ranked against the others - the running totals
+// are therefore bounded by the number of distinct label sets seen so far, not by `limit`; only
+// Result()'s output is bounded by `limit`. A true limit-sized running bound isn't safe here: an
+// evicted name can't be distinguished from one that's genuinely done growing, so it would let a
+// late-arriving reply for an already-evicted name under-count (see
+// TestVolumeAccumulator_SumsAcrossArrivalOrder for the wrong-winner case this previously caused).
+// This still beats holding one full VolumeResponse per ingester, since identical names across
+// ingesters collapse into one counter.
+type volumeAccumulator struct {
+	mtx    sync.Mutex
+	limit  int32
+	totals map[string]uint64
+}
+
+func newVolumeAccumulator(limit int32) *volumeAccumulator {
+	return &volumeAccumulator{
+		limit:  limit,
+		totals: make(map[string]uint64),
+	}
+}
+
+func (a *volumeAccumulator) Add(resp *logproto.VolumeResponse) {
+	if resp == nil {
+		return
 	}
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	for _, v := range resp.Volumes {
+		a.totals[v.Name] += v.Volume
+	}
+}
+
+// Result returns the top-K volumes (by total across every folded response), sorted by descending
+// volume.
+func (a *volumeAccumulator) Result() *logproto.VolumeResponse {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	h := make(volumeHeap, 0, len(a.totals))
+	for name, volume := range a.totals {
+		if a.limit > 0 && int32(h.Len()) >= a.limit {
+			if volume <= h[0].volume {
+				continue
+			}
+			heap.Pop(&h)
+		}
+		heap.Push(&h, volumeHeapItem{name: name, volume: volume})
+	}
+
+	items := []volumeHeapItem(h)
+	sort.Slice(items, func(i, j int) bool { return items[i].volume > items[j].volume })
 
-	merged := index_stats.MergeStats(casted...)
-	return &merged, nil
+	resp := &logproto.VolumeResponse{Volumes: make([]logproto.Volume, 0, len(items))}
+	for _, item := range items {
+		resp.Volumes = append(resp.Volumes, logproto.Volume{Name: item.name, Volume: item.volume})
+	}
+	return resp
 }
 
 func (q *IngesterQuerier) Volume(ctx context.Context, _ string, from, through model.Time, limit int32, targetLabels []string, aggregateBy string, matchers ...*labels.Matcher) (*logproto.VolumeResponse, error) {
+	ctx, logQueryWarnings := q.ensureQueryWarnings(ctx)
+	defer logQueryWarnings()
+
 	matcherString := "{}"
 	if len(matchers) > 0 {
 		matcherString = syntax.MatchersString(matchers)
 	}
 
-	resps, err := q.forAllIngesters(ctx, func(ctx context.Context, querierClient logproto.QuerierClient) (interface{}, error) {
+	acc := newVolumeAccumulator(limit)
+	err := q.forAllIngestersStreaming(ctx, func(_ string, resp interface{}) error {
+		acc.Add(resp.(*logproto.VolumeResponse))
+		return nil
+	}, func(ctx context.Context, querierClient logproto.QuerierClient) (interface{}, error) {
 		return querierClient.GetVolume(ctx, &logproto.VolumeRequest{
 			From:         from,
 			Through:      through,
@@ -477,46 +1105,39 @@ func (q *IngesterQuerier) Volume(ctx context.Context, _ string, from, through mo
 		return nil, err
 	}
 
-	casted := make([]*logproto.VolumeResponse, 0, len(resps))
-	for _, resp := range resps {
-		casted = append(casted, resp.response.(*logproto.VolumeResponse))
-	}
-
-	merged := seriesvolume.Merge(casted, limit)
-	return merged, nil
+	return acc.Result(), nil
 }
 
 func (q *IngesterQuerier) DetectedLabel(ctx context.Context, req *logproto.DetectedLabelsRequest) (*logproto.LabelToValuesResponse, error) {
-	ingesterResponses, err := q.forAllIngesters(ctx, func(ctx context.Context, client logproto.QuerierClient) (interface{}, error) {
-		return client.GetDetectedLabels(ctx, req)
-	})
-	if err != nil {
-		level.Error(q.logger).Log("msg", "error getting detected labels", "err", err)
-		return nil, err
-	}
+	ctx, logQueryWarnings := q.ensureQueryWarnings(ctx)
+	defer logQueryWarnings()
 
+	var mtx sync.Mutex
 	labelMap := make(map[string][]string)
-	for _, resp := range ingesterResponses {
-		thisIngester, ok := resp.response.(*logproto.LabelToValuesResponse)
+
+	err := q.forAllIngestersStreaming(ctx, func(_ string, resp interface{}) error {
+		thisIngester, ok := resp.(*logproto.LabelToValuesResponse)
 		if !ok {
 			level.Warn(q.logger).Log("msg", "Cannot convert response to LabelToValuesResponse in detectedlabels",
 				"response", resp)
+			return nil
 		}
-
 		if thisIngester == nil {
-			continue
+			return nil
 		}
 
+		mtx.Lock()
+		defer mtx.Unlock()
 		for label, thisIngesterValues := range thisIngester.Labels {
-			var combinedValues []string
-			allIngesterValues, isLabelPresent := labelMap[label]
-			if isLabelPresent {
-				combinedValues = append(allIngesterValues, thisIngesterValues.Values...)
-			} else {
-				combinedValues = thisIngesterValues.Values
-			}
-			labelMap[label] = combinedValues
+			labelMap[label] = append(labelMap[label], thisIngesterValues.Values...)
 		}
+		return nil
+	}, func(ctx context.Context, client logproto.QuerierClient) (interface{}, error) {
+		return client.GetDetectedLabels(ctx, req)
+	})
+	if err != nil {
+		level.Error(q.logger).Log("msg", "error getting detected labels", "err", err)
+		return nil, err
 	}
 
 	// Dedupe all ingester values
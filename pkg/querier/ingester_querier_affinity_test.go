@@ -0,0 +1,38 @@
+package querier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngesterAffinityCache_HitWithFewerThanFullReplicationSet(t *testing.T) {
+	c := NewIngesterAffinityCache(time.Minute, "test_affinity_hit")
+	qf := queryFingerprint{tenantID: "tenant-a", fingerprint: 42}
+
+	// A quorum-satisfied query typically returns fewer responses than the full replication
+	// factor, so the cache commonly stores fewer addresses than RF.
+	c.Put(qf, []string{"10.0.0.1", "10.0.0.2"})
+
+	addrs, ok := c.Get(qf)
+	require.True(t, ok)
+	require.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, addrs)
+}
+
+func TestIngesterAffinityCache_MissAfterTTLExpiry(t *testing.T) {
+	c := NewIngesterAffinityCache(-time.Minute, "test_affinity_ttl")
+	qf := queryFingerprint{tenantID: "tenant-a", fingerprint: 42}
+
+	c.Put(qf, []string{"10.0.0.1"})
+
+	_, ok := c.Get(qf)
+	require.False(t, ok)
+}
+
+func TestIngesterAffinityCache_MissForUnknownFingerprint(t *testing.T) {
+	c := NewIngesterAffinityCache(time.Minute, "test_affinity_miss")
+
+	_, ok := c.Get(queryFingerprint{tenantID: "tenant-a", fingerprint: 1})
+	require.False(t, ok)
+}
@@ -0,0 +1,34 @@
+package querier
+
+import (
+	"testing"
+
+	"github.com/grafana/dskit/ring"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeetsMinimumSuccessFraction_DefaultsToQuorum(t *testing.T) {
+	q := &IngesterQuerier{}
+	rs := ring.ReplicationSet{
+		Instances: make([]ring.InstanceDesc, 3),
+		MaxErrors: 1,
+	}
+
+	// Quorum for RF=3 with MaxErrors=1 tolerates one failure: 2 of 3 succeeding must pass...
+	require.True(t, q.meetsMinimumSuccessFraction(rs, 2))
+	// ...but 1 of 3 (two failures) must not, matching what ring.DoUntilQuorum would have required.
+	require.False(t, q.meetsMinimumSuccessFraction(rs, 1))
+}
+
+func TestMeetsMinimumSuccessFraction_ExplicitConfig(t *testing.T) {
+	q := &IngesterQuerier{}
+	q.querierConfig.PartialDataMinSuccessFraction = 0.5
+	rs := ring.ReplicationSet{
+		Instances: make([]ring.InstanceDesc, 4),
+		MaxErrors: 1,
+	}
+
+	// An explicit, looser fraction overrides the replication set's own quorum fraction.
+	require.True(t, q.meetsMinimumSuccessFraction(rs, 2))
+	require.False(t, q.meetsMinimumSuccessFraction(rs, 1))
+}
@@ -0,0 +1,136 @@
+package querier
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	index_stats "github.com/grafana/loki/v3/pkg/storage/stores/index/stats"
+)
+
+func TestStatsAccumulator(t *testing.T) {
+	acc := &statsAccumulator{}
+	acc.Add(&index_stats.Stats{Streams: 1, Chunks: 2, Bytes: 3, Entries: 4})
+	acc.Add(&index_stats.Stats{Streams: 5, Chunks: 6, Bytes: 7, Entries: 8})
+	acc.Add(nil)
+
+	require.Equal(t, &index_stats.Stats{Streams: 6, Chunks: 8, Bytes: 10, Entries: 12}, acc.Result())
+}
+
+func TestVolumeAccumulator_RetainsTopKOnly(t *testing.T) {
+	acc := newVolumeAccumulator(2)
+	acc.Add(&logproto.VolumeResponse{Volumes: []logproto.Volume{
+		{Name: `{a="1"}`, Volume: 10},
+		{Name: `{a="2"}`, Volume: 50},
+		{Name: `{a="3"}`, Volume: 5},
+	}})
+	acc.Add(&logproto.VolumeResponse{Volumes: []logproto.Volume{
+		{Name: `{a="4"}`, Volume: 100},
+	}})
+
+	result := acc.Result()
+	require.Len(t, result.Volumes, 2)
+	require.Equal(t, `{a="4"}`, result.Volumes[0].Name)
+	require.Equal(t, uint64(100), result.Volumes[0].Volume)
+	require.Equal(t, `{a="2"}`, result.Volumes[1].Name)
+	require.Equal(t, uint64(50), result.Volumes[1].Volume)
+}
+
+func TestVolumeAccumulator_SumsRepeatedNames(t *testing.T) {
+	acc := newVolumeAccumulator(10)
+	acc.Add(&logproto.VolumeResponse{Volumes: []logproto.Volume{{Name: `{a="1"}`, Volume: 10}}})
+	acc.Add(&logproto.VolumeResponse{Volumes: []logproto.Volume{{Name: `{a="1"}`, Volume: 5}}})
+
+	result := acc.Result()
+	require.Len(t, result.Volumes, 1)
+	require.Equal(t, uint64(15), result.Volumes[0].Volume)
+}
+
+// TestVolumeAccumulator_SumsAcrossArrivalOrder is a regression test: a name that looked small on
+// an early response (and would have been evicted by a naive streaming top-K heap) must still win
+// once a later response's contribution is added, because a name's ranking depends on its total
+// across every ingester, not just the ingesters seen so far.
+func TestVolumeAccumulator_SumsAcrossArrivalOrder(t *testing.T) {
+	acc := newVolumeAccumulator(1)
+	acc.Add(&logproto.VolumeResponse{Volumes: []logproto.Volume{
+		{Name: `{a="x"}`, Volume: 10},
+		{Name: `{a="y"}`, Volume: 20},
+	}})
+	acc.Add(&logproto.VolumeResponse{Volumes: []logproto.Volume{
+		{Name: `{a="x"}`, Volume: 15},
+	}})
+
+	result := acc.Result()
+	require.Len(t, result.Volumes, 1)
+	require.Equal(t, `{a="x"}`, result.Volumes[0].Name)
+	require.Equal(t, uint64(25), result.Volumes[0].Volume)
+}
+
+// TestVolumeAccumulator_ManyDistinctNames exercises Result()'s top-K selection over a running
+// total map much larger than `limit`, to make sure the right tail survives the bound.
+func TestVolumeAccumulator_ManyDistinctNames(t *testing.T) {
+	const limit = 100
+
+	acc := newVolumeAccumulator(limit)
+	for i := 0; i < 100_000; i++ {
+		acc.Add(&logproto.VolumeResponse{Volumes: []logproto.Volume{
+			{Name: labelSetName(i), Volume: uint64(i)},
+		}})
+	}
+
+	result := acc.Result()
+	require.Len(t, result.Volumes, limit)
+	// The highest-volume label sets (the last `limit` generated, since Volume increases with i)
+	// must be the ones retained.
+	require.Equal(t, uint64(99_999), result.Volumes[0].Volume)
+}
+
+// TestVolumeAccumulator_RunningTotalsGrowWithCardinalityNotLimit documents the accumulator's
+// actual memory characteristic: the running totals grow with the number of distinct label sets
+// seen so far, not with `limit`. A `limit`-sized running bound isn't achievable without
+// reintroducing the wrong-winner bug from TestVolumeAccumulator_SumsAcrossArrivalOrder, since a
+// name's true total isn't known until every ingester's contribution has been folded in.
+func TestVolumeAccumulator_RunningTotalsGrowWithCardinalityNotLimit(t *testing.T) {
+	const limit = 10
+	const distinctNames = 1_000
+
+	acc := newVolumeAccumulator(limit)
+	for i := 0; i < distinctNames; i++ {
+		acc.Add(&logproto.VolumeResponse{Volumes: []logproto.Volume{
+			{Name: labelSetName(i), Volume: uint64(i)},
+		}})
+		require.Len(t, acc.totals, i+1)
+	}
+
+	require.Len(t, acc.totals, distinctNames)
+	require.Len(t, acc.Result().Volumes, limit)
+}
+
+func labelSetName(i int) string {
+	return `{a="` + string(rune('a'+i%26)) + `", i="` + strconv.Itoa(i) + `"}`
+}
+
+func BenchmarkVolumeAccumulator_Add(b *testing.B) {
+	acc := newVolumeAccumulator(100)
+	resp := &logproto.VolumeResponse{Volumes: []logproto.Volume{
+		{Name: `{cluster="a", namespace="b"}`, Volume: 1234},
+	}}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		resp.Volumes[0].Name = labelSetName(i)
+		acc.Add(resp)
+	}
+}
+
+func BenchmarkStatsAccumulator_Add(b *testing.B) {
+	acc := &statsAccumulator{}
+	resp := &index_stats.Stats{Streams: 1, Chunks: 2, Bytes: 3, Entries: 4}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		acc.Add(resp)
+	}
+}
@@ -0,0 +1,32 @@
+package querier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnsureQueryWarnings_AttachesWhenAbsent(t *testing.T) {
+	q := &IngesterQuerier{logger: log.NewNopLogger()}
+
+	ctx, drain := q.ensureQueryWarnings(context.Background())
+	ExtractQueryWarnings(ctx).addPartialFailures([]PartialFailure{{Addr: "10.0.0.1", Err: context.DeadlineExceeded}})
+
+	require.Len(t, ExtractQueryWarnings(ctx).PartialFailures(), 1)
+	drain() // must not panic even though nothing reads the logged warning in this test
+}
+
+func TestEnsureQueryWarnings_ReusesExistingWarnings(t *testing.T) {
+	q := &IngesterQuerier{logger: log.NewNopLogger()}
+
+	outer := ContextWithQueryWarnings(context.Background())
+	inner, drain := q.ensureQueryWarnings(outer)
+	ExtractQueryWarnings(inner).addPartialFailures([]PartialFailure{{Addr: "10.0.0.1", Err: context.DeadlineExceeded}})
+
+	// The failure recorded via the context returned by ensureQueryWarnings must be visible through
+	// the original context too, i.e. ensureQueryWarnings didn't attach a second, shadowing one.
+	require.Len(t, ExtractQueryWarnings(outer).PartialFailures(), 1)
+	drain()
+}